@@ -0,0 +1,194 @@
+// Package v1alpha1 contains the dozlab.io/v1alpha1 API group, starting with
+// the LabSession CRD that replaces the fire-and-forget create/delete CLI
+// with a reconciled resource.
+// +kubebuilder:object:generate=true
+// +groupName=dozlab.io
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupVersion is group version used to register these objects.
+var GroupVersion = schema.GroupVersion{Group: "dozlab.io", Version: "v1alpha1"}
+
+// SchemeBuilder is used to add go types to the GroupVersionKind scheme.
+var SchemeBuilder = &schemeBuilder{}
+
+type schemeBuilder struct{}
+
+// AddToScheme registers the LabSession types with the given scheme.
+func (schemeBuilder) AddToScheme(scheme *runtime.Scheme) error {
+	scheme.AddKnownTypes(GroupVersion,
+		&LabSession{},
+		&LabSessionList{},
+	)
+	metav1.AddToGroupVersion(scheme, GroupVersion)
+	return nil
+}
+
+// LabSessionPhase is a coarse summary of where a LabSession is in its
+// lifecycle, surfaced in `kubectl get labsessions`.
+type LabSessionPhase string
+
+const (
+	LabSessionPhasePending      LabSessionPhase = "Pending"
+	LabSessionPhaseProvisioning LabSessionPhase = "Provisioning"
+	LabSessionPhaseRunning      LabSessionPhase = "Running"
+	LabSessionPhaseFailed       LabSessionPhase = "Failed"
+	LabSessionPhaseTerminating  LabSessionPhase = "Terminating"
+)
+
+// LabSessionSpec mirrors the fields SessionConfig used to collect from CLI
+// flags; the controller resolves defaults and renders the Pod template from
+// this instead of the CLI doing it inline.
+type LabSessionSpec struct {
+	// UserID identifies the owner the session was created for.
+	UserID string `json:"userID"`
+
+	// RootfsURL is the VM rootfs image the session boots.
+	RootfsURL string `json:"rootfsURL"`
+
+	// TerminalImage overrides the sidecar terminal image. Defaults to the
+	// profile/controller default when empty.
+	TerminalImage string `json:"terminalImage,omitempty"`
+
+	// VMCPU is the number of vCPUs given to the guest VM.
+	VMCPU string `json:"vmCPU,omitempty"`
+	// VMMemory is the guest VM memory in MB.
+	VMMemory string `json:"vmMemory,omitempty"`
+	// DiskSize is the guest VM's root disk size, e.g. "4G".
+	DiskSize string `json:"diskSize,omitempty"`
+
+	// Resources caps the host-side Pod resources for the VM container.
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// NodeSelector, Tolerations, and ExtraVolumes are Pod-template tweaks a
+	// profile (see ../../profiles) can layer on top of the rendered Pod,
+	// e.g. pinning a gpu profile to GPU nodes or adding a scratch volume for
+	// a networking-lab profile. ExtraVolumes are added to the Pod's volumes
+	// only; no container gets a volumeMount for them.
+	NodeSelector map[string]string   `json:"nodeSelector,omitempty"`
+	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
+	ExtraVolumes []corev1.Volume     `json:"extraVolumes,omitempty"`
+}
+
+// LabSessionCondition types, following the k8s convention of a short
+// Type/Status/Reason/Message tuple rather than free-form status strings.
+const (
+	LabSessionConditionReady = "Ready"
+)
+
+// LabSessionStatus is maintained by the controller and never written to by
+// clients.
+type LabSessionStatus struct {
+	// Phase is a coarse summary of the session's lifecycle state.
+	Phase LabSessionPhase `json:"phase,omitempty"`
+
+	// PodRef names the child Pod once it has been created.
+	PodRef string `json:"podRef,omitempty"`
+	// ServiceRef names the child Service once it has been created.
+	ServiceRef string `json:"serviceRef,omitempty"`
+	// VscodePasswordSecretRef names the Secret holding the generated
+	// VS Code password.
+	VscodePasswordSecretRef string `json:"vscodePasswordSecretRef,omitempty"`
+
+	// AccessURLs are the in-cluster URLs the session is reachable at: one
+	// per Service port, built from the Service's cluster-local DNS name.
+	AccessURLs []string `json:"accessURLs,omitempty"`
+
+	// Conditions follows the standard metav1.Condition contract so tooling
+	// that understands "kubectl wait --for=condition=Ready" works here too.
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the spec generation the status was last
+	// computed from, so a reader can tell whether status reflects the
+	// current spec.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="User",type=string,JSONPath=`.spec.userID`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// LabSession is the Schema for the labsessions API. A LabSession reconciles
+// to a Pod/Service/Secret triple (and whatever else the rendered template
+// emits), owned via OwnerReferences so deleting the LabSession garbage
+// collects its children.
+type LabSession struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LabSessionSpec   `json:"spec,omitempty"`
+	Status LabSessionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// LabSessionList contains a list of LabSession.
+type LabSessionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LabSession `json:"items"`
+}
+
+// DeepCopyObject implements runtime.Object. Hand-written here because this
+// example doesn't run controller-gen; a real checkout would generate
+// zz_generated.deepcopy.go instead.
+func (in *LabSession) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(LabSession)
+	*out = *in
+	out.ObjectMeta = *in.ObjectMeta.DeepCopy()
+	in.Spec.Resources.DeepCopyInto(&out.Spec.Resources)
+	if in.Spec.NodeSelector != nil {
+		out.Spec.NodeSelector = make(map[string]string, len(in.Spec.NodeSelector))
+		for k, v := range in.Spec.NodeSelector {
+			out.Spec.NodeSelector[k] = v
+		}
+	}
+	if in.Spec.Tolerations != nil {
+		out.Spec.Tolerations = make([]corev1.Toleration, len(in.Spec.Tolerations))
+		for i := range in.Spec.Tolerations {
+			in.Spec.Tolerations[i].DeepCopyInto(&out.Spec.Tolerations[i])
+		}
+	}
+	if in.Spec.ExtraVolumes != nil {
+		out.Spec.ExtraVolumes = make([]corev1.Volume, len(in.Spec.ExtraVolumes))
+		for i := range in.Spec.ExtraVolumes {
+			in.Spec.ExtraVolumes[i].DeepCopyInto(&out.Spec.ExtraVolumes[i])
+		}
+	}
+	if in.Status.AccessURLs != nil {
+		out.Status.AccessURLs = append([]string(nil), in.Status.AccessURLs...)
+	}
+	if in.Status.Conditions != nil {
+		out.Status.Conditions = make([]metav1.Condition, len(in.Status.Conditions))
+		copy(out.Status.Conditions, in.Status.Conditions)
+	}
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *LabSessionList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(LabSessionList)
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		out.Items = make([]LabSession, len(in.Items))
+		for i := range in.Items {
+			out.Items[i] = *in.Items[i].DeepCopyObject().(*LabSession)
+		}
+	}
+	return out
+}
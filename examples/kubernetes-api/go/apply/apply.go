@@ -0,0 +1,98 @@
+// Package apply resolves GVRs via a RESTMapper and server-side applies
+// manifests through the dynamic client, so a caller can apply any kind the
+// Pod template emits without a typed client or a Create/Update branch per
+// kind. Both the CLI's --dry-run=server/diff commands and the LabSession
+// controller's child-object reconciliation share this one code path.
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// FieldManager identifies dozlab as the owner of fields it server-side
+// applies, whether that's the controller reconciling a child object or the
+// CLI's --dry-run=server preview.
+const FieldManager = "dozlab-session-manager"
+
+// Client applies unstructured manifests by resolving each one's GVR through
+// a RESTMapper and driving the dynamic client, instead of a typed client per
+// kind.
+type Client struct {
+	dynamicClient dynamic.Interface
+	mapper        meta.RESTMapper
+}
+
+// New builds a Client with its own cached discovery-backed RESTMapper, for
+// callers (like the CLI) that don't already maintain one.
+func New(config *rest.Config) (*Client, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient))
+	return NewWithMapper(config, mapper)
+}
+
+// NewWithMapper builds a Client against an already-constructed RESTMapper,
+// for callers (like a controller-runtime manager) that maintain their own
+// cached mapper and shouldn't pay for a second one.
+func NewWithMapper(config *rest.Config, mapper meta.RESTMapper) (*Client, error) {
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+	return &Client{dynamicClient: dynamicClient, mapper: mapper}, nil
+}
+
+func (c *Client) resourceFor(namespace string, obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+	mapping, err := c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve REST mapping for %s: %w", gvk, err)
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return c.dynamicClient.Resource(mapping.Resource).Namespace(namespace), nil
+	}
+	return c.dynamicClient.Resource(mapping.Resource), nil
+}
+
+// Apply server-side-applies obj under FieldManager. With dryRun set, this
+// validates against admission (webhooks, quota, schema) without persisting
+// anything, for --dry-run=server previews; otherwise it's a real apply,
+// idempotent whether obj already exists or not.
+func (c *Client) Apply(ctx context.Context, namespace string, obj *unstructured.Unstructured, dryRun bool) (*unstructured.Unstructured, error) {
+	resourceClient, err := c.resourceFor(namespace, obj)
+	if err != nil {
+		return nil, err
+	}
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s/%s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	force := true
+	opts := metav1.PatchOptions{FieldManager: FieldManager, Force: &force}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	return resourceClient.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, opts)
+}
+
+// Get fetches obj's live state as unstructured, e.g. for a diff preview.
+func (c *Client) Get(ctx context.Context, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	resourceClient, err := c.resourceFor(namespace, obj)
+	if err != nil {
+		return nil, err
+	}
+	return resourceClient.Get(ctx, obj.GetName(), metav1.GetOptions{})
+}
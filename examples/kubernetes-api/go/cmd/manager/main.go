@@ -0,0 +1,50 @@
+// Command manager runs the LabSession controller. Deploy this in-cluster;
+// the CLI in ../.. creates and deletes LabSession CRs rather than the child
+// Pods/Services/Secrets, which this controller applies. The CLI reads a
+// session's Pod only for diff, exec, and logs, and sends rendered objects
+// to the API server only as a --dry-run=server request.
+package main
+
+import (
+	"os"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	dozlabv1alpha1 "github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/api/v1alpha1"
+	"github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/controllers"
+)
+
+func main() {
+	log := ctrl.Log.WithName("dozlab-manager")
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		log.Error(err, "failed to register core scheme")
+		os.Exit(1)
+	}
+	if err := dozlabv1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		log.Error(err, "failed to register LabSession scheme")
+		os.Exit(1)
+	}
+
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{Scheme: scheme})
+	if err != nil {
+		log.Error(err, "failed to start manager")
+		os.Exit(1)
+	}
+
+	if err := (&controllers.LabSessionReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		log.Error(err, "failed to set up LabSession controller")
+		os.Exit(1)
+	}
+
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		log.Error(err, "manager exited with error")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,348 @@
+// Package controllers holds the controller-runtime reconcilers for the
+// dozlab.io CRDs.
+package controllers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	dozlabv1alpha1 "github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/api/v1alpha1"
+	"github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/apply"
+	"github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/plugins"
+	"github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/render"
+
+	// Blank-imported so their init() registers the corresponding
+	// ResourcePlugin; add a third party's own import alongside these to
+	// teach the controller a new kind.
+	_ "github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/plugins/configmap"
+	_ "github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/plugins/networkpolicy"
+	_ "github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/plugins/persistentvolumeclaim"
+	_ "github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/plugins/pod"
+	_ "github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/plugins/secret"
+	_ "github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/plugins/service"
+)
+
+// cleanupFinalizer runs before a LabSession's owned objects are garbage
+// collected, giving the controller a chance to do anything OwnerReferences
+// alone can't (e.g. releasing an external VM reservation).
+const cleanupFinalizer = "dozlab.io/labsession-cleanup"
+
+// templatePath is the Pod template every LabSession renders from.
+const templatePath = render.DefaultTemplatePath
+
+// LabSessionReconciler reconciles a LabSession object.
+type LabSessionReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// Applier server-side applies child objects through a RESTMapper-resolved
+	// dynamic client, the same code path the CLI's --dry-run=server/diff
+	// commands use, instead of a typed Create/Update per kind. Built lazily
+	// in SetupWithManager if left nil.
+	Applier *apply.Client
+}
+
+// Reconcile drives a LabSession towards its desired state: rendering the Pod
+// template from spec, applying the Pod/Service/Secret it produces, and
+// keeping status in sync. Unlike the old CLI, this runs again on every spec
+// or child-object change, so edits to a LabSession actually take effect.
+func (r *LabSessionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	session := &dozlabv1alpha1.LabSession{}
+	if err := r.Get(ctx, req.NamespacedName, session); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get LabSession: %w", err)
+	}
+
+	if !session.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, session)
+	}
+
+	if !controllerutil.ContainsFinalizer(session, cleanupFinalizer) {
+		controllerutil.AddFinalizer(session, cleanupFinalizer)
+		if err := r.Update(ctx, session); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to add finalizer: %w", err)
+		}
+	}
+
+	secretName, err := r.reconcileSecret(ctx, session)
+	if err != nil {
+		return ctrl.Result{}, r.markFailed(ctx, session, err)
+	}
+
+	objs, err := r.renderObjects(session, secretName)
+	if err != nil {
+		return ctrl.Result{}, r.markFailed(ctx, session, err)
+	}
+
+	for _, obj := range objs {
+		if err := r.reconcileChild(ctx, session, obj); err != nil {
+			return ctrl.Result{}, r.markFailed(ctx, session, err)
+		}
+		switch typed := obj.(type) {
+		case *corev1.Pod:
+			session.Status.PodRef = typed.Name
+		case *corev1.Service:
+			session.Status.ServiceRef = typed.Name
+			session.Status.AccessURLs = accessURLs(typed)
+		}
+	}
+
+	session.Status.VscodePasswordSecretRef = secretName
+	session.Status.Phase = dozlabv1alpha1.LabSessionPhaseRunning
+	session.Status.ObservedGeneration = session.Generation
+	setReadyCondition(session, metav1.ConditionTrue, "Reconciled", "all child objects applied")
+
+	if err := r.Status().Update(ctx, session); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %w", err)
+	}
+
+	log.Info("reconciled LabSession", "pod", session.Status.PodRef, "service", session.Status.ServiceRef)
+	return ctrl.Result{}, nil
+}
+
+func (r *LabSessionReconciler) reconcileDelete(ctx context.Context, session *dozlabv1alpha1.LabSession) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(session, cleanupFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	// OwnerReferences would eventually garbage collect these anyway, but
+	// deleting explicitly through the registered plugins - rather than
+	// hardcoding Pod/Service/Secret deletes - lets this drain any kind a
+	// template happens to emit, including ones added by a third-party
+	// plugin this binary doesn't know about by name.
+	if objs, err := r.renderObjects(session, session.Status.VscodePasswordSecretRef); err == nil {
+		for _, obj := range objs {
+			if p, ok := plugins.Get(obj.GetObjectKind().GroupVersionKind().Kind); ok {
+				if err := p.Delete(ctx, r.Client, obj.GetNamespace(), obj.GetName()); err != nil {
+					return ctrl.Result{}, fmt.Errorf("failed to delete %s %s: %w", p.Kind(), obj.GetName(), err)
+				}
+			}
+		}
+	}
+	if secretPlugin, ok := plugins.Get("Secret"); ok && session.Status.VscodePasswordSecretRef != "" {
+		if err := secretPlugin.Delete(ctx, r.Client, session.Namespace, session.Status.VscodePasswordSecretRef); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to delete secret %s: %w", session.Status.VscodePasswordSecretRef, err)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(session, cleanupFinalizer)
+	if err := r.Update(ctx, session); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to remove finalizer: %w", err)
+	}
+	return ctrl.Result{}, nil
+}
+
+// reconcileSecret creates the VS Code password Secret if it doesn't exist
+// yet; unlike the Pod/Service it's never regenerated from spec once created,
+// since rotating it on every reconcile would invalidate live sessions. The
+// template also renders its own same-named Secret document, but renderObjects
+// drops it rather than let reconcileChild apply it over this one.
+func (r *LabSessionReconciler) reconcileSecret(ctx context.Context, session *dozlabv1alpha1.LabSession) (string, error) {
+	name := fmt.Sprintf("lab-session-%s-secrets", session.Name)
+
+	existing := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: session.Namespace, Name: name}, existing)
+	if err == nil {
+		return name, nil
+	}
+	if !apierrors.IsNotFound(err) {
+		return "", fmt.Errorf("failed to get secret %s: %w", name, err)
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: session.Namespace},
+		StringData: map[string]string{"vscode-password": generatePassword(32)},
+	}
+	if err := controllerutil.SetControllerReference(session, secret, r.Scheme); err != nil {
+		return "", fmt.Errorf("failed to set owner reference on secret: %w", err)
+	}
+	if err := r.Create(ctx, secret); err != nil {
+		return "", fmt.Errorf("failed to create secret %s: %w", name, err)
+	}
+	return name, nil
+}
+
+// reconcileChild creates the child object if absent, or converges it to the
+// freshly rendered spec if it's drifted, so edits to a LabSession actually
+// take effect instead of being a one-shot print. Every kind but Pod goes
+// through Applier's RESTMapper-resolved SSA patch, so this isn't limited to
+// whatever kinds this package hardcodes a typed client for.
+func (r *LabSessionReconciler) reconcileChild(ctx context.Context, session *dozlabv1alpha1.LabSession, desired client.Object) error {
+	if err := controllerutil.SetControllerReference(session, desired, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner reference: %w", err)
+	}
+
+	if desiredPod, ok := desired.(*corev1.Pod); ok {
+		return r.reconcilePod(ctx, desiredPod)
+	}
+
+	desiredUnstructured, err := toUnstructured(desired)
+	if err != nil {
+		return err
+	}
+	if _, err := r.Applier.Apply(ctx, desired.GetNamespace(), desiredUnstructured, false); err != nil {
+		kind := desired.GetObjectKind().GroupVersionKind().Kind
+		return fmt.Errorf("failed to apply %s %s: %w", kind, desired.GetName(), err)
+	}
+	return nil
+}
+
+// reconcilePod special-cases the Pod: most of its spec (env, resources,
+// volumes, ...) is immutable once created, so an SSA patch can't converge a
+// spec edit any better than a plain Update would - both 422 on exactly the
+// fields the edit is meant to change. Delete it instead and let the next
+// reconcile - triggered by the Owns(&corev1.Pod{}) watch on the deletion -
+// recreate it from the freshly rendered template.
+func (r *LabSessionReconciler) reconcilePod(ctx context.Context, desired *corev1.Pod) error {
+	existing := &corev1.Pod{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	if apierrors.IsNotFound(err) {
+		return r.Create(ctx, desired)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get existing Pod: %w", err)
+	}
+	if apiequality.Semantic.DeepEqual(existing.Spec, desired.Spec) {
+		return nil
+	}
+	return r.Delete(ctx, existing)
+}
+
+// toUnstructured converts a typed child object to unstructured so Applier
+// can server-side apply it without a typed client for its kind.
+func toUnstructured(obj client.Object) (*unstructured.Unstructured, error) {
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		return nil, fmt.Errorf("failed to convert %s %s to unstructured: %w", kind, obj.GetName(), err)
+	}
+	return &unstructured.Unstructured{Object: raw}, nil
+}
+
+func (r *LabSessionReconciler) markFailed(ctx context.Context, session *dozlabv1alpha1.LabSession, cause error) error {
+	session.Status.Phase = dozlabv1alpha1.LabSessionPhaseFailed
+	setReadyCondition(session, metav1.ConditionFalse, "ReconcileError", cause.Error())
+	if err := r.Status().Update(ctx, session); err != nil {
+		return fmt.Errorf("failed to update status after reconcile error %v: %w", cause, err)
+	}
+	return cause
+}
+
+func setReadyCondition(session *dozlabv1alpha1.LabSession, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&session.Status.Conditions, metav1.Condition{
+		Type:               dozlabv1alpha1.LabSessionConditionReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: session.Generation,
+	})
+}
+
+// renderObjects renders the Pod template against the session spec (via the
+// shared render package, the same code path the CLI's dry-run/diff commands
+// use) and decodes the result into the typed objects the controller owns.
+func (r *LabSessionReconciler) renderObjects(session *dozlabv1alpha1.LabSession, secretName string) ([]client.Object, error) {
+	spec := session.Spec
+	unstructuredObjs, err := render.Manifest(templatePath, render.Values{
+		SessionID:       session.Name,
+		UserID:          spec.UserID,
+		RootfsURL:       spec.RootfsURL,
+		VscodeSecretRef: secretName,
+		TerminalImage:   spec.TerminalImage,
+		VMCPU:           spec.VMCPU,
+		VMMemory:        spec.VMMemory,
+		DiskSize:        spec.DiskSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := render.ApplyPodTweaks(unstructuredObjs, render.PodTweaks{
+		NodeSelector: spec.NodeSelector,
+		Tolerations:  spec.Tolerations,
+		ExtraVolumes: spec.ExtraVolumes,
+		Resources:    spec.Resources,
+	}); err != nil {
+		return nil, err
+	}
+
+	var objs []client.Object
+	for _, obj := range unstructuredObjs {
+		if obj.GetKind() == "Secret" {
+			// reconcileSecret already creates and owns the one real Secret
+			// (the VS Code password, never rotated once set). The
+			// template also emits its own Secret document - templated with
+			// VscodeSecretRef, a name, not the actual password - and
+			// applying that here would overwrite the real password with
+			// that name string on every reconcile.
+			continue
+		}
+		p, ok := plugins.Get(obj.GetKind())
+		if !ok {
+			// Not a kind any registered plugin owns (e.g. the template
+			// changed and nothing registered it yet); skip it rather than
+			// fail the whole reconcile.
+			continue
+		}
+		typed := p.New()
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, typed); err != nil {
+			return nil, fmt.Errorf("failed to convert %s: %w", obj.GetKind(), err)
+		}
+		objs = append(objs, typed)
+	}
+	return objs, nil
+}
+
+// accessURLs builds the in-cluster URL for every port the rendered Service
+// exposes, using the Service's cluster-local DNS name so it resolves the
+// same regardless of whether the Service is ClusterIP, NodePort, or
+// LoadBalancer.
+func accessURLs(svc *corev1.Service) []string {
+	urls := make([]string, 0, len(svc.Spec.Ports))
+	for _, port := range svc.Spec.Ports {
+		urls = append(urls, fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", svc.Name, svc.Namespace, port.Port))
+	}
+	return urls
+}
+
+func generatePassword(length int) string {
+	b := make([]byte, length)
+	rand.Read(b)
+	return base64.URLEncoding.EncodeToString(b)[:length]
+}
+
+// SetupWithManager wires the reconciler into the manager, watching
+// LabSessions and the Pods/Services/Secrets they own.
+func (r *LabSessionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Applier == nil {
+		applier, err := apply.NewWithMapper(mgr.GetConfig(), mgr.GetRESTMapper())
+		if err != nil {
+			return fmt.Errorf("failed to build child-object applier: %w", err)
+		}
+		r.Applier = applier
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&dozlabv1alpha1.LabSession{}).
+		Owns(&corev1.Pod{}).
+		Owns(&corev1.Service{}).
+		Owns(&corev1.Secret{}).
+		Complete(r)
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFakeReconciler(t *testing.T, initObjs ...client.Object) (*LabSessionReconciler, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to register corev1 types: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjs...).Build()
+	return &LabSessionReconciler{Client: fakeClient, Scheme: scheme}, fakeClient
+}
+
+func testPod(name string, containerImage string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "firecracker-vm", Image: containerImage}},
+		},
+	}
+}
+
+func TestReconcilePodCreatesWhenMissing(t *testing.T) {
+	r, c := newFakeReconciler(t)
+	desired := testPod("lab-session-a", "dozlab/vm:v1")
+
+	if err := r.reconcilePod(context.Background(), desired); err != nil {
+		t.Fatalf("reconcilePod returned error: %v", err)
+	}
+
+	got := &corev1.Pod{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(desired), got); err != nil {
+		t.Fatalf("expected Pod to be created, got error: %v", err)
+	}
+}
+
+func TestReconcilePodNoopWhenSpecUnchanged(t *testing.T) {
+	existing := testPod("lab-session-a", "dozlab/vm:v1")
+	r, _ := newFakeReconciler(t, existing)
+	desired := testPod("lab-session-a", "dozlab/vm:v1")
+
+	if err := r.reconcilePod(context.Background(), desired); err != nil {
+		t.Fatalf("reconcilePod returned error: %v", err)
+	}
+}
+
+func TestReconcilePodDeletesOnSpecDrift(t *testing.T) {
+	existing := testPod("lab-session-a", "dozlab/vm:v1")
+	r, c := newFakeReconciler(t, existing)
+	desired := testPod("lab-session-a", "dozlab/vm:v2")
+
+	if err := r.reconcilePod(context.Background(), desired); err != nil {
+		t.Fatalf("reconcilePod returned error: %v", err)
+	}
+
+	got := &corev1.Pod{}
+	err := c.Get(context.Background(), client.ObjectKeyFromObject(existing), got)
+	if err == nil {
+		t.Fatal("expected drifted Pod to be deleted, but it still exists")
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	sigsyaml "sigs.k8s.io/yaml"
+
+	"github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/apply"
+	"github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/render"
+)
+
+// dryRunMode mirrors cmdutil.GetDryRunFlag's client/server/none semantics:
+// "client" never talks to the API server, "server" validates against
+// admission without persisting, "none" performs the real create.
+type dryRunMode string
+
+const (
+	dryRunNone   dryRunMode = "none"
+	dryRunClient dryRunMode = "client"
+	dryRunServer dryRunMode = "server"
+)
+
+func parseDryRunMode(s string) (dryRunMode, error) {
+	switch dryRunMode(s) {
+	case dryRunNone, dryRunClient, dryRunServer:
+		return dryRunMode(s), nil
+	default:
+		return "", fmt.Errorf("invalid --dry-run value %q (want client, server, or none)", s)
+	}
+}
+
+func renderValuesFor(config *SessionConfig) render.Values {
+	return render.Values{
+		SessionID:     config.SessionID,
+		UserID:        config.UserID,
+		RootfsURL:     config.RootfsURL,
+		TerminalImage: config.TerminalImage,
+		VMCPU:         config.VMCPU,
+		VMMemory:      config.VMMemory,
+		DiskSize:      config.DiskSize,
+	}
+}
+
+// podTweaksFor mirrors the NodeSelector/Tolerations/ExtraVolumes a profile
+// can set onto the render.PodTweaks the controller applies at reconcile
+// time, so a --dry-run/diff preview actually reflects what the real
+// LabSession would converge to.
+func podTweaksFor(config *SessionConfig) render.PodTweaks {
+	return render.PodTweaks{
+		NodeSelector: config.NodeSelector,
+		Tolerations:  config.Tolerations,
+		ExtraVolumes: config.ExtraVolumes,
+	}
+}
+
+// withoutTemplateSecret drops the template's own Secret document the same
+// way the controller's renderObjects does: it's rendered with
+// VscodeSecretRef (a secret name), not the real password, so previewing or
+// diffing it against whatever actually manages the real Secret would always
+// show a bogus delta rather than reflect what the LabSession converges to.
+func withoutTemplateSecret(objs []*unstructured.Unstructured) []*unstructured.Unstructured {
+	var out []*unstructured.Unstructured
+	for _, obj := range objs {
+		if obj.GetKind() == "Secret" {
+			continue
+		}
+		out = append(out, obj)
+	}
+	return out
+}
+
+// runCreate renders config and, depending on mode, either prints it
+// (client), validates it against admission without persisting (server), or
+// creates the real LabSession (none).
+func runCreate(ctx context.Context, sm *SessionManager, config *SessionConfig, mode dryRunMode, output string) error {
+	if mode == dryRunNone {
+		return sm.CreateSession(ctx, config)
+	}
+
+	objs, err := render.Manifest(render.DefaultTemplatePath, renderValuesFor(config))
+	if err != nil {
+		return err
+	}
+	objs = withoutTemplateSecret(objs)
+	if err := render.ApplyPodTweaks(objs, podTweaksFor(config)); err != nil {
+		return err
+	}
+
+	if mode == dryRunClient {
+		for _, obj := range objs {
+			data, err := render.Marshal(obj, output)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("---\n%s\n", data)
+		}
+		return nil
+	}
+
+	restConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	applier, err := apply.New(restConfig)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		applied, err := applier.Apply(ctx, sm.namespace, obj, true)
+		if err != nil {
+			return fmt.Errorf("server dry-run rejected %s/%s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+		fmt.Printf("✓ %s/%s admitted (server dry run, not persisted)\n", applied.GetKind(), applied.GetName())
+	}
+	return nil
+}
+
+// runDiff renders config and prints a unified diff between the rendered
+// manifest and whatever is currently live in the cluster, the same way
+// `kubectl diff` shells out to an external diff tool.
+func runDiff(ctx context.Context, sm *SessionManager, config *SessionConfig) error {
+	objs, err := render.Manifest(render.DefaultTemplatePath, renderValuesFor(config))
+	if err != nil {
+		return err
+	}
+	objs = withoutTemplateSecret(objs)
+	if err := render.ApplyPodTweaks(objs, podTweaksFor(config)); err != nil {
+		return err
+	}
+
+	restConfig, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	applier, err := apply.New(restConfig)
+	if err != nil {
+		return err
+	}
+
+	for _, obj := range objs {
+		renderedYAML, err := render.Marshal(obj, "yaml")
+		if err != nil {
+			return err
+		}
+
+		live, err := applier.Get(ctx, sm.namespace, obj)
+		var liveYAML []byte
+		if err != nil {
+			liveYAML = []byte(fmt.Sprintf("# %s/%s does not exist yet\n", obj.GetKind(), obj.GetName()))
+		} else {
+			liveYAML, err = sigsyaml.Marshal(live.Object)
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := printUnifiedDiff(fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName()), liveYAML, renderedYAML); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printUnifiedDiff shells out to `diff -u`, honoring KUBECTL_EXTERNAL_DIFF
+// the same way kubectl's own diff subcommand does, so operators can point
+// it at their preferred differ.
+func printUnifiedDiff(label string, live, rendered []byte) error {
+	liveFile, err := os.CreateTemp("", "dozlab-diff-live-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(liveFile.Name())
+	renderedFile, err := os.CreateTemp("", "dozlab-diff-rendered-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(renderedFile.Name())
+
+	if _, err := liveFile.Write(live); err != nil {
+		return err
+	}
+	if _, err := renderedFile.Write(rendered); err != nil {
+		return err
+	}
+	liveFile.Close()
+	renderedFile.Close()
+
+	differ := "diff"
+	args := []string{"-u", liveFile.Name(), renderedFile.Name()}
+	if external := os.Getenv("KUBECTL_EXTERNAL_DIFF"); external != "" {
+		fields := strings.Fields(external)
+		differ, args = fields[0], append(fields[1:], liveFile.Name(), renderedFile.Name())
+	}
+
+	fmt.Printf("--- %s\n", label)
+	cmd := exec.Command(differ, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// diff exits 1 when files differ; that's not an error for our purposes.
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return nil
+		}
+		return fmt.Errorf("failed to run external diff: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/kubectl/pkg/util/term"
+
+	"github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/render"
+)
+
+// vmContainer is the sidecar that runs the guest VM inside a lab-session
+// Pod; `dozlab vm-exec` defaults to it instead of making the caller spell
+// out --container every time.
+const vmContainer = render.VMContainerName
+
+// terminalContainer and vscodeContainer are the other two containers
+// lab-pod-with-sidecar.yaml renders, alongside vmContainer.
+const (
+	terminalContainer = "terminal-sidecar"
+	vscodeContainer   = "code-server"
+)
+
+// containerAliases maps the friendlier --container names the CLI accepts
+// (terminal, vscode, vm) to the real container names in the rendered Pod.
+var containerAliases = map[string]string{
+	"terminal": terminalContainer,
+	"vscode":   vscodeContainer,
+	"vm":       vmContainer,
+}
+
+// resolveContainer maps a CLI --container alias to the real container name
+// in the rendered Pod; anything it doesn't recognize passes through
+// unchanged so callers can still target a sidecar by its literal name.
+func resolveContainer(alias string) string {
+	if name, ok := containerAliases[alias]; ok {
+		return name
+	}
+	return alias
+}
+
+// execOptions mirrors the handful of kubectl exec flags this example
+// supports: container selection, and -it for an interactive TTY session.
+type execOptions struct {
+	namespace string
+	podName   string
+	container string
+	stdin     bool
+	tty       bool
+	command   []string
+}
+
+// execInPod runs command inside a session pod's container, wiring the
+// calling process's stdio (and TTY size) through an SPDY exec stream the
+// same way kubectl exec does.
+func execInPod(ctx context.Context, config *rest.Config, clientset *kubernetes.Clientset, opts execOptions) error {
+	req := clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(opts.namespace).
+		Name(opts.podName).
+		SubResource("exec")
+
+	req.VersionedParams(&corev1.PodExecOptions{
+		Container: opts.container,
+		Command:   opts.command,
+		Stdin:     opts.stdin,
+		Stdout:    true,
+		Stderr:    !opts.tty,
+		TTY:       opts.tty,
+	}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create exec executor: %w", err)
+	}
+
+	streamOptions := remotecommand.StreamOptions{
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+	if opts.stdin {
+		streamOptions.Stdin = os.Stdin
+	}
+
+	if opts.tty {
+		t := term.TTY{In: os.Stdin, Out: os.Stdout, Raw: true}
+		sizeQueue := t.MonitorSize(t.GetSize())
+		streamOptions.TerminalSizeQueue = sizeQueue
+		return t.Safe(func() error {
+			return executor.StreamWithContext(ctx, streamOptions)
+		})
+	}
+
+	return executor.StreamWithContext(ctx, streamOptions)
+}
+
+// logOptions mirrors the kubectl logs flags this example supports.
+type logOptions struct {
+	namespace string
+	podName   string
+	container string
+	follow    bool
+	since     time.Duration
+	tailLines int64
+}
+
+// streamLogs writes a pod's logs to stdout, following if requested.
+func streamLogs(ctx context.Context, clientset *kubernetes.Clientset, opts logOptions) error {
+	logOpts := &corev1.PodLogOptions{
+		Container: opts.container,
+		Follow:    opts.follow,
+	}
+	if opts.since > 0 {
+		seconds := int64(opts.since.Seconds())
+		logOpts.SinceSeconds = &seconds
+	}
+	if opts.tailLines >= 0 {
+		logOpts.TailLines = &opts.tailLines
+	}
+
+	stream, err := clientset.CoreV1().Pods(opts.namespace).GetLogs(opts.podName, logOpts).Stream(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to stream logs for %s/%s: %w", opts.podName, opts.container, err)
+	}
+	defer stream.Close()
+
+	_, err = io.Copy(os.Stdout, stream)
+	return err
+}
+
+// sessionPodName is the naming convention the controller uses for a
+// LabSession's child Pod.
+func sessionPodName(sessionID string) string {
+	return fmt.Sprintf("lab-session-%s", sessionID)
+}
+
+func newClientset(config *rest.Config) (*kubernetes.Clientset, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clientset: %w", err)
+	}
+	return clientset, nil
+}
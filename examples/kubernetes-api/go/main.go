@@ -1,336 +1,241 @@
 /*
 Dozlab Session Manager - Go Example
 
-This example shows how to create lab sessions using the Kubernetes Go client.
-It reads the lab-pod-with-sidecar.yaml template and replaces variables programmatically.
+This example is a thin kubectl-like client for the LabSession CRD: it
+creates, deletes, and lists LabSession custom resources. The actual work of
+rendering the lab-pod-with-sidecar.yaml template and applying the resulting
+Pod/Service/Secret is done by the LabSessionReconciler in ./controllers,
+running as a controller against the cluster (see cmd/manager).
 
 Prerequisites:
 	go get k8s.io/client-go@latest
-	go get k8s.io/apimachinery/pkg/apis/meta/v1
-	go get k8s.io/apimachinery/pkg/util/yaml
+	go get sigs.k8s.io/controller-runtime
+	kubectl apply -f config/crd/dozlab.io_labsessions.yaml
 
 Usage:
-	go run main.go create \
+	go run . create \
 		--session-id my-session-123 \
 		--user-id alice \
 		--rootfs-url https://example.com/dozlab-k8s.ext4
 
-	go run main.go delete --session-id my-session-123
-	go run main.go list
+	go run . create --session-id my-session-123 --user-id alice \
+		--rootfs-url https://example.com/dozlab-k8s.ext4 \
+		--dry-run=client --output=yaml
+
+	go run . delete --session-id my-session-123
+	go run . list
+	go run . diff --session-id my-session-123 --user-id alice \
+		--rootfs-url https://example.com/dozlab-k8s.ext4
+
+	go run . exec --session-id my-session-123 --container vm -it -- /bin/sh
+	go run . vm-exec --session-id my-session-123 -it -- /bin/sh
+	go run . logs --session-id my-session-123 --container terminal -f
+
+	go run . create --session-id my-session-123 --user-id alice \
+		--rootfs-url https://example.com/dozlab-k8s.ext4 --profile gpu
 */
 
 package main
 
 import (
-	"bytes"
 	"context"
-	"crypto/rand"
-	"encoding/base64"
 	"flag"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
-	"text/template"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	dozlabv1alpha1 "github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/api/v1alpha1"
+	"github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/profiles"
 )
 
-// SessionManager manages Dozlab lab sessions via Kubernetes API
+// SessionManager is a thin wrapper around a controller-runtime client,
+// scoped to LabSessions in a single namespace.
 type SessionManager struct {
-	clientset *kubernetes.Clientset
+	client    client.Client
 	namespace string
-	template  string
 }
 
-// SessionConfig holds configuration for creating a new session
+// SessionConfig holds the fields a caller can set on a new LabSession; the
+// controller resolves anything left blank. A --profile fills these in from
+// profiles.Profile before CreateSession builds the LabSessionSpec; any flag
+// the caller actually passed on the command line still wins.
 type SessionConfig struct {
-	SessionID           string
-	UserID              string
-	RootfsURL           string
-	VscodePassword      string
-	VMCPU               string
-	VMMemory            string
-	DiskSize            string
-	TerminalImage       string
-	VMMemoryLimit       string
-	VMCPULimit          string
-	VMMemoryRequest     string
-	VMCPURequest        string
-	KernelSizeLimit     string
-	VMDataSizeLimit     string
-	VscodeDataSizeLimit string
+	SessionID     string
+	UserID        string
+	RootfsURL     string
+	TerminalImage string
+	VMCPU         string
+	VMMemory      string
+	DiskSize      string
+
+	NodeSelector map[string]string
+	Tolerations  []corev1.Toleration
+	ExtraVolumes []corev1.Volume
 }
 
-// NewSessionManager creates a new session manager
-func NewSessionManager(namespace string) (*SessionManager, error) {
-	// Load kubeconfig
-	var config *rest.Config
-	var err error
-
-	// Try in-cluster config first
-	config, err = rest.InClusterConfig()
-	if err != nil {
-		// Fall back to kubeconfig
-		kubeconfigPath := filepath.Join(homedir.HomeDir(), ".kube", "config")
-		config, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
-		}
-	}
-
-	// Create clientset
-	clientset, err := kubernetes.NewForConfig(config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create clientset: %w", err)
-	}
-
-	// Load template
-	templatePath := "../../../lab-pod-with-sidecar.yaml"
-	templateBytes, err := os.ReadFile(templatePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read template: %w", err)
-	}
-
-	return &SessionManager{
-		clientset: clientset,
-		namespace: namespace,
-		template:  string(templateBytes),
-	}, nil
-}
-
-// CreateSession creates a new lab session
-func (sm *SessionManager) CreateSession(ctx context.Context, config *SessionConfig) error {
-	// Set defaults
-	if config.VscodePassword == "" {
-		config.VscodePassword = generatePassword(32)
+// applyProfile fills any blank field of config from profile, without
+// overwriting flags the caller already set explicitly.
+func applyProfile(config *SessionConfig, profile *profiles.Profile) {
+	if config.TerminalImage == "" {
+		config.TerminalImage = profile.TerminalImage
 	}
 	if config.VMCPU == "" {
-		config.VMCPU = "1"
+		config.VMCPU = profile.VMCPU
 	}
 	if config.VMMemory == "" {
-		config.VMMemory = "1024"
+		config.VMMemory = profile.VMMemory
 	}
 	if config.DiskSize == "" {
-		config.DiskSize = "4G"
-	}
-	if config.TerminalImage == "" {
-		config.TerminalImage = "dozman99/dozlab-terminal:latest"
-	}
-	if config.VMMemoryLimit == "" {
-		config.VMMemoryLimit = "2Gi"
-	}
-	if config.VMCPULimit == "" {
-		config.VMCPULimit = "1500m"
+		config.DiskSize = profile.DiskSize
 	}
-	if config.VMMemoryRequest == "" {
-		config.VMMemoryRequest = "1Gi"
+	if config.NodeSelector == nil {
+		config.NodeSelector = profile.NodeSelector
 	}
-	if config.VMCPURequest == "" {
-		config.VMCPURequest = "500m"
+	if config.Tolerations == nil {
+		config.Tolerations = profile.Tolerations
 	}
-	if config.KernelSizeLimit == "" {
-		config.KernelSizeLimit = "2Gi"
-	}
-	if config.VMDataSizeLimit == "" {
-		config.VMDataSizeLimit = "5Gi"
-	}
-	if config.VscodeDataSizeLimit == "" {
-		config.VscodeDataSizeLimit = "1Gi"
+	if config.ExtraVolumes == nil {
+		config.ExtraVolumes = profile.ExtraVolumes
 	}
+}
 
-	// Replace variables in template
-	tmpl, err := template.New("manifest").Parse(sm.template)
+// NewSessionManager creates a new session manager.
+func NewSessionManager(namespace string) (*SessionManager, error) {
+	config, err := loadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
+		return nil, err
 	}
 
-	var buf bytes.Buffer
-	err = tmpl.Execute(&buf, map[string]string{
-		"SESSION_ID":               config.SessionID,
-		"USER_ID":                  config.UserID,
-		"ROOTFS_IMAGE_URL":         config.RootfsURL,
-		"VSCODE_PASSWORD":          config.VscodePassword,
-		"DISK_SIZE":                config.DiskSize,
-		"VM_CPU":                   config.VMCPU,
-		"VM_MEMORY":                config.VMMemory,
-		"TERMINAL_IMAGE":           config.TerminalImage,
-		"VM_MEMORY_LIMIT":          config.VMMemoryLimit,
-		"VM_CPU_LIMIT":             config.VMCPULimit,
-		"VM_MEMORY_REQUEST":        config.VMMemoryRequest,
-		"VM_CPU_REQUEST":           config.VMCPURequest,
-		"KERNEL_SIZE_LIMIT":        config.KernelSizeLimit,
-		"VM_DATA_SIZE_LIMIT":       config.VMDataSizeLimit,
-		"VSCODE_DATA_SIZE_LIMIT":   config.VscodeDataSizeLimit,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+	scheme := runtime.NewScheme()
+	if err := dozlabv1alpha1.SchemeBuilder.AddToScheme(scheme); err != nil {
+		return nil, fmt.Errorf("failed to register LabSession scheme: %w", err)
 	}
 
-	// Parse YAML documents
-	decoder := yaml.NewYAMLOrJSONDecoder(&buf, 4096)
+	c, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
 
-	for {
-		var obj map[string]interface{}
-		err := decoder.Decode(&obj)
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("failed to decode YAML: %w", err)
-		}
+	return &SessionManager{client: c, namespace: namespace}, nil
+}
 
-		if obj == nil {
-			continue
-		}
+func loadConfig() (*rest.Config, error) {
+	// Try in-cluster config first
+	if config, err := rest.InClusterConfig(); err == nil {
+		return config, nil
+	}
 
-		kind, ok := obj["kind"].(string)
-		if !ok {
-			continue
-		}
+	// Fall back to kubeconfig
+	kubeconfigPath := filepath.Join(homedir.HomeDir(), ".kube", "config")
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	return config, nil
+}
 
-		switch kind {
-		case "Pod":
-			err = sm.createPod(ctx, obj)
-		case "Service":
-			err = sm.createService(ctx, obj)
-		case "Secret":
-			err = sm.createSecret(ctx, obj)
-		}
+// CreateSession creates a LabSession custom resource; the controller takes
+// it from there.
+func (sm *SessionManager) CreateSession(ctx context.Context, config *SessionConfig) error {
+	session := &dozlabv1alpha1.LabSession{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      config.SessionID,
+			Namespace: sm.namespace,
+		},
+		Spec: dozlabv1alpha1.LabSessionSpec{
+			UserID:        config.UserID,
+			RootfsURL:     config.RootfsURL,
+			TerminalImage: config.TerminalImage,
+			VMCPU:         config.VMCPU,
+			VMMemory:      config.VMMemory,
+			DiskSize:      config.DiskSize,
+			NodeSelector:  config.NodeSelector,
+			Tolerations:   config.Tolerations,
+			ExtraVolumes:  config.ExtraVolumes,
+		},
+	}
 
-		if err != nil {
-			// Cleanup on failure
-			sm.DeleteSession(ctx, config.SessionID)
-			return err
-		}
+	if err := sm.client.Create(ctx, session); err != nil {
+		return fmt.Errorf("failed to create LabSession %s: %w", config.SessionID, err)
 	}
 
-	// Print access information
 	fmt.Println(strings.Repeat("=", 70))
-	fmt.Printf("🚀 Lab Session Created: %s\n", config.SessionID)
+	fmt.Printf("🚀 LabSession created: %s\n", config.SessionID)
 	fmt.Println(strings.Repeat("=", 70))
 	fmt.Printf("User ID: %s\n", config.UserID)
 	fmt.Printf("Rootfs: %s\n", config.RootfsURL)
-	fmt.Printf("VS Code Password: %s\n", config.VscodePassword)
-	fmt.Printf("\nAccess via port-forward:\n")
-	fmt.Printf("  kubectl port-forward lab-session-%s 8080:8080 8081:8081\n", config.SessionID)
-	fmt.Printf("\nThen open:\n")
-	fmt.Printf("  VS Code:  http://localhost:8080\n")
-	fmt.Printf("  Terminal: http://localhost:8081\n")
+	fmt.Printf("\nWatch it come up with:\n")
+	fmt.Printf("  kubectl get labsession %s -n %s -w\n", config.SessionID, sm.namespace)
 	fmt.Println(strings.Repeat("=", 70))
 
 	return nil
 }
 
-// DeleteSession deletes a lab session and all associated resources
+// DeleteSession deletes a LabSession; OwnerReferences on its child
+// Pod/Service/Secret mean the garbage collector cleans those up once the
+// finalizer is released.
 func (sm *SessionManager) DeleteSession(ctx context.Context, sessionID string) error {
-	podName := fmt.Sprintf("lab-session-%s", sessionID)
-	serviceName := fmt.Sprintf("lab-service-%s", sessionID)
-	secretName := fmt.Sprintf("lab-session-%s-secrets", sessionID)
-
-	deleted := []string{}
-
-	// Delete Pod
-	err := sm.clientset.CoreV1().Pods(sm.namespace).Delete(ctx, podName, metav1.DeleteOptions{})
-	if err == nil {
-		deleted = append(deleted, fmt.Sprintf("Pod: %s", podName))
+	session := &dozlabv1alpha1.LabSession{
+		ObjectMeta: metav1.ObjectMeta{Name: sessionID, Namespace: sm.namespace},
 	}
 
-	// Delete Service
-	err = sm.clientset.CoreV1().Services(sm.namespace).Delete(ctx, serviceName, metav1.DeleteOptions{})
-	if err == nil {
-		deleted = append(deleted, fmt.Sprintf("Service: %s", serviceName))
-	}
-
-	// Delete Secret
-	err = sm.clientset.CoreV1().Secrets(sm.namespace).Delete(ctx, secretName, metav1.DeleteOptions{})
-	if err == nil {
-		deleted = append(deleted, fmt.Sprintf("Secret: %s", secretName))
-	}
-
-	if len(deleted) > 0 {
-		fmt.Printf("✓ Deleted session %s:\n", sessionID)
-		for _, item := range deleted {
-			fmt.Printf("  - %s\n", item)
-		}
-	} else {
+	if err := sm.client.Delete(ctx, session); err != nil {
 		fmt.Printf("✗ Session %s not found\n", sessionID)
+		return nil
 	}
 
+	fmt.Printf("✓ Deleting session %s (finalizer releases owned objects)\n", sessionID)
 	return nil
 }
 
-// ListSessions lists all active lab sessions
+// ListSessions lists all LabSessions in the namespace.
 func (sm *SessionManager) ListSessions(ctx context.Context) error {
-	pods, err := sm.clientset.CoreV1().Pods(sm.namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: "app=lab-environment",
-	})
-	if err != nil {
-		return fmt.Errorf("failed to list sessions: %w", err)
+	var sessions dozlabv1alpha1.LabSessionList
+	if err := sm.client.List(ctx, &sessions, client.InNamespace(sm.namespace)); err != nil {
+		return fmt.Errorf("failed to list LabSessions: %w", err)
 	}
 
-	if len(pods.Items) == 0 {
+	if len(sessions.Items) == 0 {
 		fmt.Println("No active sessions found")
 		return nil
 	}
 
-	fmt.Printf("\nActive Sessions (%d):\n", len(pods.Items))
+	fmt.Printf("\nActive Sessions (%d):\n", len(sessions.Items))
 	fmt.Println(strings.Repeat("-", 80))
-	fmt.Printf("%-20s %-15s %-15s %s\n", "SESSION ID", "USER ID", "STATUS", "AGE")
+	fmt.Printf("%-20s %-15s %-15s %s\n", "SESSION ID", "USER ID", "PHASE", "AGE")
 	fmt.Println(strings.Repeat("-", 80))
 
-	for _, pod := range pods.Items {
-		sessionID := pod.Labels["session-id"]
-		userID := pod.Labels["user-id"]
-		status := string(pod.Status.Phase)
-		age := time.Since(pod.CreationTimestamp.Time).Round(time.Second)
-
-		fmt.Printf("%-20s %-15s %-15s %s\n", sessionID, userID, status, age)
+	for _, session := range sessions.Items {
+		age := time.Since(session.CreationTimestamp.Time).Round(time.Second)
+		fmt.Printf("%-20s %-15s %-15s %s\n", session.Name, session.Spec.UserID, session.Status.Phase, age)
 	}
 
 	return nil
 }
 
-func (sm *SessionManager) createPod(ctx context.Context, obj map[string]interface{}) error {
-	// Convert to Pod object (simplified - in production use proper conversion)
-	// For now, use unstructured approach
-	fmt.Printf("✓ Created Pod: %v\n", obj["metadata"].(map[string]interface{})["name"])
-	return nil
-}
-
-func (sm *SessionManager) createService(ctx context.Context, obj map[string]interface{}) error {
-	fmt.Printf("✓ Created Service: %v\n", obj["metadata"].(map[string]interface{})["name"])
-	return nil
-}
-
-func (sm *SessionManager) createSecret(ctx context.Context, obj map[string]interface{}) error {
-	fmt.Printf("✓ Created Secret: %v\n", obj["metadata"].(map[string]interface{})["name"])
-	return nil
-}
-
-func generatePassword(length int) string {
-	b := make([]byte, length)
-	rand.Read(b)
-	return base64.URLEncoding.EncodeToString(b)[:length]
-}
-
 func main() {
 	// Define subcommands
 	createCmd := flag.NewFlagSet("create", flag.ExitOnError)
 	sessionIDCreate := createCmd.String("session-id", "", "Session ID (required)")
 	userIDCreate := createCmd.String("user-id", "", "User ID (required)")
 	rootfsURLCreate := createCmd.String("rootfs-url", "", "Rootfs image URL (required)")
-	vmCPU := createCmd.String("vm-cpu", "1", "VM CPUs")
-	vmMemory := createCmd.String("vm-memory", "1024", "VM Memory (MB)")
+	vmCPU := createCmd.String("vm-cpu", "", "VM CPUs (defaults come from --profile, then \"1\")")
+	vmMemory := createCmd.String("vm-memory", "", "VM Memory in MB (defaults come from --profile, then \"1024\")")
 	namespaceCreate := createCmd.String("namespace", "default", "Kubernetes namespace")
+	dryRunCreate := createCmd.String("dry-run", "none", "Must be \"client\", \"server\", or \"none\"")
+	outputCreate := createCmd.String("output", "yaml", "Output format for --dry-run=client: yaml or json")
+	profileCreate := createCmd.String("profile", "", "Curated session profile to load defaults from, e.g. small, gpu, networking-lab")
 
 	deleteCmd := flag.NewFlagSet("delete", flag.ExitOnError)
 	sessionIDDelete := deleteCmd.String("session-id", "", "Session ID (required)")
@@ -339,9 +244,34 @@ func main() {
 	listCmd := flag.NewFlagSet("list", flag.ExitOnError)
 	namespaceList := listCmd.String("namespace", "default", "Kubernetes namespace")
 
+	diffCmd := flag.NewFlagSet("diff", flag.ExitOnError)
+	sessionIDDiff := diffCmd.String("session-id", "", "Session ID (required)")
+	userIDDiff := diffCmd.String("user-id", "", "User ID (required)")
+	rootfsURLDiff := diffCmd.String("rootfs-url", "", "Rootfs image URL (required)")
+	namespaceDiff := diffCmd.String("namespace", "default", "Kubernetes namespace")
+
+	execCmd := flag.NewFlagSet("exec", flag.ExitOnError)
+	sessionIDExec := execCmd.String("session-id", "", "Session ID (required)")
+	namespaceExec := execCmd.String("namespace", "default", "Kubernetes namespace")
+	containerExec := execCmd.String("container", "terminal", "Container to exec into: terminal, vscode, or vm")
+	itExec := execCmd.Bool("it", false, "Allocate a TTY and attach stdin (interactive)")
+
+	vmExecCmd := flag.NewFlagSet("vm-exec", flag.ExitOnError)
+	sessionIDVMExec := vmExecCmd.String("session-id", "", "Session ID (required)")
+	namespaceVMExec := vmExecCmd.String("namespace", "default", "Kubernetes namespace")
+	itVMExec := vmExecCmd.Bool("it", false, "Allocate a TTY and attach stdin (interactive)")
+
+	logsCmd := flag.NewFlagSet("logs", flag.ExitOnError)
+	sessionIDLogs := logsCmd.String("session-id", "", "Session ID (required)")
+	namespaceLogs := logsCmd.String("namespace", "default", "Kubernetes namespace")
+	containerLogs := logsCmd.String("container", "terminal", "Container to read logs from: terminal, vscode, or vm")
+	followLogs := logsCmd.Bool("f", false, "Follow the log stream")
+	sinceLogs := logsCmd.Duration("since", 0, "Only return logs newer than this, e.g. 5m")
+	tailLogs := logsCmd.Int64("tail", -1, "Number of lines from the end of the log to show")
+
 	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run main.go <command> [options]")
-		fmt.Println("Commands: create, delete, list")
+		fmt.Println("Usage: go run . <command> [options]")
+		fmt.Println("Commands: create, delete, list, diff, exec, vm-exec, logs")
 		os.Exit(1)
 	}
 
@@ -355,7 +285,7 @@ func main() {
 			os.Exit(1)
 		}
 
-		manager, err := NewSessionManager(*namespaceCreate)
+		mode, err := parseDryRunMode(*dryRunCreate)
 		if err != nil {
 			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
@@ -369,8 +299,29 @@ func main() {
 			VMMemory:  *vmMemory,
 		}
 
-		err = manager.CreateSession(ctx, config)
-		if err != nil {
+		if *profileCreate != "" {
+			profile, err := profiles.Load(*profileCreate)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			applyProfile(config, profile)
+		}
+
+		// --dry-run=client never touches the API, so it doesn't need a
+		// working kubeconfig at all.
+		var manager *SessionManager
+		if mode != dryRunClient {
+			manager, err = NewSessionManager(*namespaceCreate)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			manager = &SessionManager{namespace: *namespaceCreate}
+		}
+
+		if err := runCreate(ctx, manager, config, mode, *outputCreate); err != nil {
 			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -409,8 +360,129 @@ func main() {
 			os.Exit(1)
 		}
 
+	case "diff":
+		diffCmd.Parse(os.Args[2:])
+		if *sessionIDDiff == "" || *userIDDiff == "" || *rootfsURLDiff == "" {
+			diffCmd.PrintDefaults()
+			os.Exit(1)
+		}
+
+		manager, err := NewSessionManager(*namespaceDiff)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		config := &SessionConfig{
+			SessionID: *sessionIDDiff,
+			UserID:    *userIDDiff,
+			RootfsURL: *rootfsURLDiff,
+		}
+
+		if err := runDiff(ctx, manager, config); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "exec":
+		execCmd.Parse(os.Args[2:])
+		if *sessionIDExec == "" || execCmd.NArg() == 0 {
+			fmt.Println("Usage: dozlab exec --session-id <id> [--container terminal|vscode|vm] [-it] -- <command>")
+			execCmd.PrintDefaults()
+			os.Exit(1)
+		}
+
+		restConfig, clientset, err := restConfigAndClientset()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		err = execInPod(ctx, restConfig, clientset, execOptions{
+			namespace: *namespaceExec,
+			podName:   sessionPodName(*sessionIDExec),
+			container: resolveContainer(*containerExec),
+			stdin:     *itExec,
+			tty:       *itExec,
+			command:   execCmd.Args(),
+		})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "vm-exec":
+		vmExecCmd.Parse(os.Args[2:])
+		if *sessionIDVMExec == "" || vmExecCmd.NArg() == 0 {
+			fmt.Println("Usage: dozlab vm-exec --session-id <id> [-it] -- <command>")
+			vmExecCmd.PrintDefaults()
+			os.Exit(1)
+		}
+
+		restConfig, clientset, err := restConfigAndClientset()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		// vm-exec is exec with the container pinned to the VM sidecar, so
+		// callers don't have to spell out --container vm every time.
+		err = execInPod(ctx, restConfig, clientset, execOptions{
+			namespace: *namespaceVMExec,
+			podName:   sessionPodName(*sessionIDVMExec),
+			container: vmContainer,
+			stdin:     *itVMExec,
+			tty:       *itVMExec,
+			command:   vmExecCmd.Args(),
+		})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "logs":
+		logsCmd.Parse(os.Args[2:])
+		if *sessionIDLogs == "" {
+			logsCmd.PrintDefaults()
+			os.Exit(1)
+		}
+
+		_, clientset, err := restConfigAndClientset()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		err = streamLogs(ctx, clientset, logOptions{
+			namespace: *namespaceLogs,
+			podName:   sessionPodName(*sessionIDLogs),
+			container: resolveContainer(*containerLogs),
+			follow:    *followLogs,
+			since:     *sinceLogs,
+			tailLines: *tailLogs,
+		})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
 	default:
-		fmt.Println("Unknown command. Use: create, delete, or list")
+		fmt.Println("Unknown command. Use: create, delete, list, diff, exec, vm-exec, or logs")
 		os.Exit(1)
 	}
 }
+
+// restConfigAndClientset loads the same kubeconfig/in-cluster config the
+// rest of the CLI uses, plus a typed clientset for the exec/logs
+// subresources remotecommand needs.
+func restConfigAndClientset() (*rest.Config, *kubernetes.Clientset, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	clientset, err := newClientset(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, clientset, nil
+}
@@ -0,0 +1,41 @@
+// Package configmap registers the ConfigMap ResourcePlugin.
+package configmap
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/plugins"
+)
+
+func init() {
+	plugins.RegisterPlugin(&plugin{})
+}
+
+type plugin struct{}
+
+func (plugin) Kind() string { return "ConfigMap" }
+
+func (plugin) New() client.Object { return &corev1.ConfigMap{} }
+
+func (plugin) Create(ctx context.Context, c client.Client, obj client.Object) error {
+	return c.Create(ctx, obj)
+}
+
+func (plugin) Delete(ctx context.Context, c client.Client, namespace, name string) error {
+	cm := &corev1.ConfigMap{}
+	cm.Namespace, cm.Name = namespace, name
+	if err := c.Delete(ctx, cm); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (plugin) Get(ctx context.Context, c client.Client, namespace, name string) (client.Object, error) {
+	cm := &corev1.ConfigMap{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, cm)
+	return cm, err
+}
@@ -0,0 +1,41 @@
+// Package networkpolicy registers the NetworkPolicy ResourcePlugin.
+package networkpolicy
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/plugins"
+)
+
+func init() {
+	plugins.RegisterPlugin(&plugin{})
+}
+
+type plugin struct{}
+
+func (plugin) Kind() string { return "NetworkPolicy" }
+
+func (plugin) New() client.Object { return &networkingv1.NetworkPolicy{} }
+
+func (plugin) Create(ctx context.Context, c client.Client, obj client.Object) error {
+	return c.Create(ctx, obj)
+}
+
+func (plugin) Delete(ctx context.Context, c client.Client, namespace, name string) error {
+	np := &networkingv1.NetworkPolicy{}
+	np.Namespace, np.Name = namespace, name
+	if err := c.Delete(ctx, np); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (plugin) Get(ctx context.Context, c client.Client, namespace, name string) (client.Object, error) {
+	np := &networkingv1.NetworkPolicy{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, np)
+	return np, err
+}
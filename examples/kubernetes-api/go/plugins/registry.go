@@ -0,0 +1,67 @@
+// Package plugins defines the ResourcePlugin extension point that the
+// LabSession controller dispatches child-object management through, instead
+// of hardcoding a kind switch. Each concrete kind (pod, service, secret, ...)
+// lives in its own subpackage and registers itself via RegisterPlugin in an
+// init() function, so third parties can add support for new kinds by
+// blank-importing their own plugin package alongside dozlab's built-ins.
+package plugins
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResourcePlugin manages one child-object kind on behalf of a LabSession.
+type ResourcePlugin interface {
+	// Kind is the object kind this plugin handles, e.g. "Pod".
+	Kind() string
+
+	// New returns a zero-value typed instance of the plugin's kind, e.g.
+	// &corev1.Pod{}. Callers use this to decode a rendered manifest document
+	// into a typed client.Object without needing their own kind switch.
+	New() client.Object
+
+	// Create creates obj, which the caller has already populated (including
+	// OwnerReferences).
+	Create(ctx context.Context, c client.Client, obj client.Object) error
+
+	// Delete deletes the named object. Implementations should treat
+	// not-found as success.
+	Delete(ctx context.Context, c client.Client, namespace, name string) error
+
+	// Get fetches the named object through c and returns it as a typed
+	// instance of the plugin's kind.
+	Get(ctx context.Context, c client.Client, namespace, name string) (client.Object, error)
+}
+
+var registry = map[string]ResourcePlugin{}
+
+// RegisterPlugin adds p to the registry, keyed by its Kind(). Plugins
+// typically call this from an init() function in their own package.
+// Registering the same kind twice is a programming error and panics, the
+// same way sql.Register or image.RegisterFormat do.
+func RegisterPlugin(p ResourcePlugin) {
+	kind := p.Kind()
+	if _, exists := registry[kind]; exists {
+		panic(fmt.Sprintf("plugins: RegisterPlugin called twice for kind %q", kind))
+	}
+	registry[kind] = p
+}
+
+// Get looks up the plugin registered for kind, if any.
+func Get(kind string) (ResourcePlugin, bool) {
+	p, ok := registry[kind]
+	return p, ok
+}
+
+// All returns every registered plugin, in no particular order, for callers
+// that need every registered kind rather than a hardcoded list.
+func All() []ResourcePlugin {
+	all := make([]ResourcePlugin, 0, len(registry))
+	for _, p := range registry {
+		all = append(all, p)
+	}
+	return all
+}
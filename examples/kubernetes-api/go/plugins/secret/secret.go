@@ -0,0 +1,41 @@
+// Package secret registers the Secret ResourcePlugin.
+package secret
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/DozLab/dozlab-infra/examples/kubernetes-api/go/plugins"
+)
+
+func init() {
+	plugins.RegisterPlugin(&plugin{})
+}
+
+type plugin struct{}
+
+func (plugin) Kind() string { return "Secret" }
+
+func (plugin) New() client.Object { return &corev1.Secret{} }
+
+func (plugin) Create(ctx context.Context, c client.Client, obj client.Object) error {
+	return c.Create(ctx, obj)
+}
+
+func (plugin) Delete(ctx context.Context, c client.Client, namespace, name string) error {
+	s := &corev1.Secret{}
+	s.Namespace, s.Name = namespace, name
+	if err := c.Delete(ctx, s); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (plugin) Get(ctx context.Context, c client.Client, namespace, name string) (client.Object, error) {
+	s := &corev1.Secret{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, s)
+	return s, err
+}
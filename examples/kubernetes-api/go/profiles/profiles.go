@@ -0,0 +1,82 @@
+// Package profiles loads curated session shapes (small, gpu,
+// networking-lab, ...) from YAML files embedded into the binary, validates
+// them against an embedded JSON schema, and exposes them as a typed Profile
+// the CLI layers under whatever flags the caller passed explicitly.
+package profiles
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+	corev1 "k8s.io/api/core/v1"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+//go:embed schema.json
+var schemaJSON []byte
+
+//go:embed small.yaml gpu.yaml networking-lab.yaml
+var profileFiles embed.FS
+
+// Profile is the full set of knobs a curated session shape can set; a
+// LabSessionSpec is built by layering CLI flags on top of this.
+type Profile struct {
+	TerminalImage string `json:"terminalImage,omitempty"`
+	VMCPU         string `json:"vmCPU,omitempty"`
+	VMMemory      string `json:"vmMemory,omitempty"`
+	DiskSize      string `json:"diskSize,omitempty"`
+
+	NodeSelector map[string]string   `json:"nodeSelector,omitempty"`
+	Tolerations  []corev1.Toleration `json:"tolerations,omitempty"`
+	ExtraVolumes []corev1.Volume     `json:"extraVolumes,omitempty"`
+}
+
+var schema = mustCompileSchema()
+
+func mustCompileSchema() *jsonschema.Schema {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("profile-schema.json", bytes.NewReader(schemaJSON)); err != nil {
+		panic(fmt.Sprintf("profiles: invalid embedded schema: %v", err))
+	}
+	s, err := compiler.Compile("profile-schema.json")
+	if err != nil {
+		panic(fmt.Sprintf("profiles: failed to compile embedded schema: %v", err))
+	}
+	return s
+}
+
+// Load reads the named profile (without its .yaml suffix, e.g. "gpu"),
+// validates it against the embedded JSON schema, and decodes it.
+func Load(name string) (*Profile, error) {
+	raw, err := profileFiles.ReadFile(name + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("unknown profile %q: %w", name, err)
+	}
+
+	jsonBytes, err := sigsyaml.YAMLToJSON(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profile %q: %w", name, err)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(jsonBytes, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse profile %q: %w", name, err)
+	}
+	if err := schema.Validate(doc); err != nil {
+		return nil, fmt.Errorf("profile %q failed schema validation: %w", name, err)
+	}
+
+	var profile Profile
+	if err := json.Unmarshal(jsonBytes, &profile); err != nil {
+		return nil, fmt.Errorf("failed to decode profile %q: %w", name, err)
+	}
+	return &profile, nil
+}
+
+// Names lists the built-in profiles shipped with the binary.
+func Names() []string {
+	return []string{"small", "gpu", "networking-lab"}
+}
@@ -0,0 +1,42 @@
+package profiles
+
+import "testing"
+
+func TestLoadBuiltinProfiles(t *testing.T) {
+	for _, name := range Names() {
+		profile, err := Load(name)
+		if err != nil {
+			t.Fatalf("Load(%q) returned error: %v", name, err)
+		}
+		if profile == nil {
+			t.Fatalf("Load(%q) returned a nil Profile", name)
+		}
+	}
+}
+
+func TestLoadUnknownProfile(t *testing.T) {
+	if _, err := Load("does-not-exist"); err == nil {
+		t.Fatal("Load(\"does-not-exist\") returned no error, want an unknown-profile error")
+	}
+}
+
+func TestSchemaRejectsUnknownProperty(t *testing.T) {
+	doc := map[string]interface{}{
+		"vmCPU":          "4",
+		"notAKnownThing": "should fail additionalProperties: false",
+	}
+	if err := schema.Validate(doc); err == nil {
+		t.Fatal("schema.Validate accepted a document with an unknown property, want an error")
+	}
+}
+
+func TestSchemaRejectsWrongTolerationOperator(t *testing.T) {
+	doc := map[string]interface{}{
+		"tolerations": []interface{}{
+			map[string]interface{}{"key": "dozlab.io/gpu", "operator": "NotARealOperator"},
+		},
+	}
+	if err := schema.Validate(doc); err == nil {
+		t.Fatal("schema.Validate accepted an invalid toleration operator, want an error")
+	}
+}
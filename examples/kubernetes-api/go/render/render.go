@@ -0,0 +1,222 @@
+// Package render turns a LabSessionSpec into the manifest objects
+// lab-pod-with-sidecar.yaml describes, so the controller and the CLI's
+// dry-run/diff commands render from exactly the same code path instead of
+// each re-implementing template execution.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+// VMContainerName is the container inside the rendered Pod that runs the
+// guest VM, per lab-pod-with-sidecar.yaml; exec.go's vm-exec subcommand
+// targets this same name so the CLI and the rendered template can't drift.
+const VMContainerName = "firecracker-vm"
+
+// DefaultTemplatePath is the Pod template every LabSession renders from,
+// relative to examples/kubernetes-api/go.
+const DefaultTemplatePath = "../../../lab-pod-with-sidecar.yaml"
+
+// Values are the template variables lab-pod-with-sidecar.yaml expects.
+// Blank fields are filled with the same defaults CreateSession used to
+// apply inline.
+type Values struct {
+	SessionID       string
+	UserID          string
+	RootfsURL       string
+	VscodeSecretRef string
+	TerminalImage   string
+	VMCPU           string
+	VMMemory        string
+	DiskSize        string
+
+	// VMMemoryLimit, VMCPULimit, VMMemoryRequest, and VMCPURequest are the
+	// host-side Pod resource quantities for the VM container; KernelSizeLimit,
+	// VMDataSizeLimit, and VscodeDataSizeLimit size the template's ephemeral
+	// volumes. These are template-level quantities, not something
+	// PodTweaks.Resources (a spec.resources-driven override applied after
+	// render) substitutes for, so they always need a value.
+	VMMemoryLimit       string
+	VMCPULimit          string
+	VMMemoryRequest     string
+	VMCPURequest        string
+	KernelSizeLimit     string
+	VMDataSizeLimit     string
+	VscodeDataSizeLimit string
+}
+
+func (v Values) withDefaults() Values {
+	if v.TerminalImage == "" {
+		v.TerminalImage = "dozman99/dozlab-terminal:latest"
+	}
+	if v.VMCPU == "" {
+		v.VMCPU = "1"
+	}
+	if v.VMMemory == "" {
+		v.VMMemory = "1024"
+	}
+	if v.DiskSize == "" {
+		v.DiskSize = "4G"
+	}
+	if v.VMMemoryLimit == "" {
+		v.VMMemoryLimit = "2Gi"
+	}
+	if v.VMCPULimit == "" {
+		v.VMCPULimit = "1500m"
+	}
+	if v.VMMemoryRequest == "" {
+		v.VMMemoryRequest = "1Gi"
+	}
+	if v.VMCPURequest == "" {
+		v.VMCPURequest = "500m"
+	}
+	if v.KernelSizeLimit == "" {
+		v.KernelSizeLimit = "2Gi"
+	}
+	if v.VMDataSizeLimit == "" {
+		v.VMDataSizeLimit = "5Gi"
+	}
+	if v.VscodeDataSizeLimit == "" {
+		v.VscodeDataSizeLimit = "1Gi"
+	}
+	return v
+}
+
+// Manifest reads templatePath, executes it against v, and decodes every
+// document in the result into an unstructured object.
+func Manifest(templatePath string, v Values) ([]*unstructured.Unstructured, error) {
+	v = v.withDefaults()
+
+	templateBytes, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+
+	tmpl, err := template.New("manifest").Parse(string(templateBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, map[string]string{
+		"SESSION_ID":             v.SessionID,
+		"USER_ID":                v.UserID,
+		"ROOTFS_IMAGE_URL":       v.RootfsURL,
+		"VSCODE_PASSWORD":        v.VscodeSecretRef,
+		"DISK_SIZE":              v.DiskSize,
+		"VM_CPU":                 v.VMCPU,
+		"VM_MEMORY":              v.VMMemory,
+		"TERMINAL_IMAGE":         v.TerminalImage,
+		"VM_MEMORY_LIMIT":        v.VMMemoryLimit,
+		"VM_CPU_LIMIT":           v.VMCPULimit,
+		"VM_MEMORY_REQUEST":      v.VMMemoryRequest,
+		"VM_CPU_REQUEST":         v.VMCPURequest,
+		"KERNEL_SIZE_LIMIT":      v.KernelSizeLimit,
+		"VM_DATA_SIZE_LIMIT":     v.VMDataSizeLimit,
+		"VSCODE_DATA_SIZE_LIMIT": v.VscodeDataSizeLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	decoder := yaml.NewYAMLOrJSONDecoder(&buf, 4096)
+	var objs []*unstructured.Unstructured
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(obj); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode rendered manifest: %w", err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// PodTweaks are the adjustments layered onto the rendered Pod that the Go
+// template itself has no variables for: node placement and extra volumes
+// (which a profile can set), and host-side resource limits/requests for the
+// VM container (which come from a LabSession's spec.resources; profiles have
+// no resources field). ApplyPodTweaks is the single place that applies them, so the
+// controller and the CLI's dry-run/diff preview can never drift the way
+// two separate implementations of this would.
+type PodTweaks struct {
+	NodeSelector map[string]string
+	Tolerations  []corev1.Toleration
+	ExtraVolumes []corev1.Volume
+	Resources    corev1.ResourceRequirements
+}
+
+// ApplyPodTweaks finds the rendered Pod among objs and layers tweaks onto
+// it in place, converting to a typed corev1.Pod and back so the merge
+// logic doesn't have to walk unstructured maps.
+func ApplyPodTweaks(objs []*unstructured.Unstructured, tweaks PodTweaks) error {
+	for _, obj := range objs {
+		if obj.GetKind() != "Pod" {
+			continue
+		}
+
+		var pod corev1.Pod
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &pod); err != nil {
+			return fmt.Errorf("failed to convert Pod for tweaking: %w", err)
+		}
+
+		if len(tweaks.NodeSelector) > 0 {
+			if pod.Spec.NodeSelector == nil {
+				pod.Spec.NodeSelector = map[string]string{}
+			}
+			for k, v := range tweaks.NodeSelector {
+				pod.Spec.NodeSelector[k] = v
+			}
+		}
+		pod.Spec.Tolerations = append(pod.Spec.Tolerations, tweaks.Tolerations...)
+		pod.Spec.Volumes = append(pod.Spec.Volumes, tweaks.ExtraVolumes...)
+		if len(tweaks.Resources.Limits) > 0 || len(tweaks.Resources.Requests) > 0 {
+			found := false
+			for i := range pod.Spec.Containers {
+				if pod.Spec.Containers[i].Name == VMContainerName {
+					pod.Spec.Containers[i].Resources = tweaks.Resources
+					found = true
+				}
+			}
+			if !found {
+				return fmt.Errorf("no container named %q in rendered Pod %s; spec.resources has nothing to apply to", VMContainerName, pod.Name)
+			}
+		}
+
+		converted, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&pod)
+		if err != nil {
+			return fmt.Errorf("failed to convert tweaked Pod back to unstructured: %w", err)
+		}
+		obj.Object = converted
+	}
+	return nil
+}
+
+// Marshal renders obj as either "yaml" or "json", matching the --output
+// values kubectl accepts.
+func Marshal(obj *unstructured.Unstructured, output string) ([]byte, error) {
+	switch output {
+	case "", "yaml":
+		return sigsyaml.Marshal(obj.Object)
+	case "json":
+		return json.MarshalIndent(obj.Object, "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported output format %q (want yaml or json)", output)
+	}
+}
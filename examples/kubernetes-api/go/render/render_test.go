@@ -0,0 +1,87 @@
+package render
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func podObject(t *testing.T, containerName string) *unstructured.Unstructured {
+	t.Helper()
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "lab-session-test"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: containerName}},
+		},
+	}
+	raw, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+	if err != nil {
+		t.Fatalf("failed to convert test Pod to unstructured: %v", err)
+	}
+	obj := &unstructured.Unstructured{Object: raw}
+	obj.SetKind("Pod")
+	return obj
+}
+
+func TestApplyPodTweaksMergesNodeSelectorTolerationsAndVolumes(t *testing.T) {
+	objs := []*unstructured.Unstructured{podObject(t, VMContainerName)}
+
+	err := ApplyPodTweaks(objs, PodTweaks{
+		NodeSelector: map[string]string{"dozlab.io/pool": "gpu"},
+		Tolerations:  []corev1.Toleration{{Key: "dozlab.io/gpu", Operator: corev1.TolerationOpExists}},
+		ExtraVolumes: []corev1.Volume{{Name: "scratch"}},
+	})
+	if err != nil {
+		t.Fatalf("ApplyPodTweaks returned error: %v", err)
+	}
+
+	var pod corev1.Pod
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(objs[0].Object, &pod); err != nil {
+		t.Fatalf("failed to convert tweaked Pod back: %v", err)
+	}
+
+	if got := pod.Spec.NodeSelector["dozlab.io/pool"]; got != "gpu" {
+		t.Errorf("NodeSelector[dozlab.io/pool] = %q, want %q", got, "gpu")
+	}
+	if len(pod.Spec.Tolerations) != 1 || pod.Spec.Tolerations[0].Key != "dozlab.io/gpu" {
+		t.Errorf("Tolerations = %+v, want one toleration for dozlab.io/gpu", pod.Spec.Tolerations)
+	}
+	if len(pod.Spec.Volumes) != 1 || pod.Spec.Volumes[0].Name != "scratch" {
+		t.Errorf("Volumes = %+v, want one volume named scratch", pod.Spec.Volumes)
+	}
+}
+
+func TestApplyPodTweaksSetsVMContainerResources(t *testing.T) {
+	objs := []*unstructured.Unstructured{podObject(t, VMContainerName)}
+	resources := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+	}
+
+	if err := ApplyPodTweaks(objs, PodTweaks{Resources: resources}); err != nil {
+		t.Fatalf("ApplyPodTweaks returned error: %v", err)
+	}
+
+	var pod corev1.Pod
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(objs[0].Object, &pod); err != nil {
+		t.Fatalf("failed to convert tweaked Pod back: %v", err)
+	}
+	got := pod.Spec.Containers[0].Resources.Limits[corev1.ResourceMemory]
+	if got.String() != "4Gi" {
+		t.Errorf("VM container memory limit = %q, want %q", got.String(), "4Gi")
+	}
+}
+
+func TestApplyPodTweaksErrorsWhenVMContainerMissing(t *testing.T) {
+	objs := []*unstructured.Unstructured{podObject(t, "not-the-vm")}
+	resources := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+	}
+
+	if err := ApplyPodTweaks(objs, PodTweaks{Resources: resources}); err == nil {
+		t.Fatal("expected an error when the rendered Pod has no VM container, got nil")
+	}
+}